@@ -0,0 +1,50 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestTLSServerNegotiatesH2 guards against customListenAndServeTLSWithConfig regressing
+// into preferring http/1.1 over h2 in its ALPN preference list: a client that offers both
+// protocols must have the connection negotiated as "h2".
+func TestTLSServerNegotiatesH2(t *testing.T) {
+	certPEM, keyPEM := generateSelfSignedCert(t, "h2-server")
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+
+	s := &ApiServer{crtBytes: certPEM, keyBytes: keyPEM, listener: ln, stoppedCh: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		<-s.StoppedCh()
+	})
+
+	var conn *tls.Conn
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err = tls.Dial("tcp", ln.Addr().String(), &tls.Config{
+			InsecureSkipVerify: true,
+			NextProtos:         []string{"h2", "http/1.1"},
+		})
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("dialing %s: %v", ln.Addr().String(), err)
+	}
+	defer conn.Close()
+
+	if proto := conn.ConnectionState().NegotiatedProtocol; proto != "h2" {
+		t.Errorf("negotiated protocol is %q, want %q", proto, "h2")
+	}
+}
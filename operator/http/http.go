@@ -3,11 +3,19 @@ package http
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/coreos/go-systemd/v22/activation"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -17,29 +25,155 @@ import (
 	mpsv1alpha1 "github.com/playfab/thundernetes/operator/api/v1alpha1"
 )
 
-var (
-	crtBytes []byte
-	keyBytes []byte
-)
-
 const (
 	listeningPort = 5000
+
+	// certReloadInterval is how often the default certificate is re-read from its source
+	// (file or Kubernetes Secret) to pick up rotations. SNI certificates are loaded once
+	// and are not reloaded.
+	certReloadInterval = 30 * time.Second
+
+	// tcpKeepAlivePeriod is how often TCP keep-alive probes are sent on accepted
+	// connections, both plain and TLS.
+	tcpKeepAlivePeriod = 3 * time.Minute
+
+	// defaultMinTLSVersion is the TLS floor used when SecureServingInfo.MinTLSVersion is
+	// left unset.
+	defaultMinTLSVersion = tls.VersionTLS12
+
+	// h2cEnvVar opts the insecure (non-TLS) listener into h2c (cleartext HTTP/2), for
+	// clients that want to hold a single long-lived multiplexed connection for bursty
+	// allocation traffic without needing TLS.
+	h2cEnvVar = "API_H2C"
+
+	// shutdownTimeoutEnvVar overrides defaultShutdownTimeout: how long Start waits for
+	// in-flight handlers (e.g. an allocation blocked on the Kubernetes API) to drain
+	// before forcibly closing the server.
+	shutdownTimeoutEnvVar = "API_SHUTDOWN_TIMEOUT"
+
+	// defaultShutdownTimeout is used when shutdownTimeoutEnvVar is unset or invalid.
+	defaultShutdownTimeout = 60 * time.Second
 )
 
+// tlsCipherSuites is a curated, modern set of TLS 1.0-1.2 cipher suites offered by the
+// server, ordered by preference. TLS 1.3 connections ignore this list and always
+// negotiate one of Go's three built-in 1.3 suites.
+var tlsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+}
+
 // ApiServer is a helper struct that implements manager.Runnable interface
 // so it can be added to our Manager
 type ApiServer struct {
 	client client.Client
 	config *rest.Config
 	scheme *runtime.Scheme
+
+	// listener, when set (e.g. via NewApiServerWithListener), is served directly instead
+	// of one obtained from systemd socket activation or net.Listen.
+	listener net.Listener
+
+	// crtBytes/keyBytes are the default certificate/key pair given to NewApiServer or
+	// NewApiServerWithListener. They, secureServingInfo below, and listener above are all
+	// per-instance fields rather than package-level variables, so that distinct ApiServer
+	// instances (e.g. in tests, or multiple managers in one process) don't clobber each
+	// other's TLS configuration.
+	crtBytes, keyBytes []byte
+
+	// secureServingInfo, when set via NewApiServerWithSecureServingInfo, takes priority
+	// over crtBytes/keyBytes and enables mutual TLS, SNI-based certificate selection and
+	// dynamic certificate reloading.
+	secureServingInfo *SecureServingInfo
+
+	// stoppedCh is closed once Start has fully stopped serving: after all non-hijacked
+	// handlers have completed, or after shutdownTimeoutEnvVar elapsed and the server was
+	// forcibly closed.
+	stoppedCh chan struct{}
+
+	// ready is 1 while the server is accepting new requests and 0 before Start begins
+	// serving or once shutdown has started; read by the /readyz handler.
+	ready int32
+}
+
+// StoppedCh returns a channel that's closed once the server has fully stopped serving.
+// It is safe to call before Start runs.
+func (s *ApiServer) StoppedCh() <-chan struct{} {
+	return s.stoppedCh
+}
+
+// SNICertKeyPair is an additional certificate/key pair served for connections whose TLS
+// ClientHello requests one of Names via SNI, on top of the server's default certificate.
+type SNICertKeyPair struct {
+	CertPEMBlock []byte
+	KeyPEMBlock  []byte
+	Names        []string
+}
+
+// SecureServingInfo holds the TLS configuration for the API server: the default
+// certificate/key pair (optionally reloaded from a file or a Kubernetes Secret), any
+// number of SNI-selected certificates, and an optional client CA bundle for mutual TLS.
+// It is modeled on how kube-apiserver's secure serving stack composes the same concerns.
+type SecureServingInfo struct {
+	// CertPEMBlock/KeyPEMBlock are the default server certificate and key, used for
+	// connections that don't match any of SNICerts.
+	CertPEMBlock []byte
+	KeyPEMBlock  []byte
+
+	// CertFile/KeyFile, when set, are watched on disk and reloaded every
+	// certReloadInterval, taking priority over CertPEMBlock/KeyPEMBlock.
+	CertFile, KeyFile string
+
+	// SecretNamespace/SecretName, when set, are watched instead of CertFile/KeyFile: the
+	// server periodically re-fetches this Kubernetes Secret and reloads its tls.crt/tls.key
+	// data, taking priority over CertFile/KeyFile.
+	SecretNamespace, SecretName string
+
+	// ClientCAPEMBlock, when set, enables mutual TLS: the server requires and verifies a
+	// client certificate signed by one of these CAs for every connection, so only trusted
+	// game allocation callers can reach /api/v1/allocate.
+	ClientCAPEMBlock []byte
+
+	// SNICerts holds additional certificate/key pairs selected by SNI host name.
+	SNICerts []SNICertKeyPair
+
+	// MinTLSVersion is the minimum TLS version (e.g. tls.VersionTLS12) the server will
+	// negotiate. Defaults to defaultMinTLSVersion when zero.
+	MinTLSVersion uint16
 }
 
 // NewApiServer creates a new ApiServer and initializes the crd/key variables (can be nil)
 func NewApiServer(mgr ctrl.Manager, crt, key []byte) error {
-	crtBytes = crt
-	keyBytes = key
+	return newApiServer(mgr, &ApiServer{crtBytes: crt, keyBytes: key})
+}
+
+// NewApiServerWithSecureServingInfo is like NewApiServer but accepts a full
+// SecureServingInfo, enabling mutual TLS, SNI-based certificate selection and dynamic
+// certificate reloading without restarting the server.
+func NewApiServerWithSecureServingInfo(mgr ctrl.Manager, info *SecureServingInfo) error {
+	return newApiServer(mgr, &ApiServer{secureServingInfo: info})
+}
+
+// NewApiServerWithListener is like NewApiServer but serves on ln instead of obtaining a
+// listener from systemd socket activation or net.Listen, so tests and embedders can
+// inject an arbitrary net.Listener (a Unix socket, a TLS-terminating sidecar's FD, a
+// bufconn, ...).
+func NewApiServerWithListener(mgr ctrl.Manager, ln net.Listener, crt, key []byte) error {
+	return newApiServer(mgr, &ApiServer{listener: ln, crtBytes: crt, keyBytes: key})
+}
 
-	server := &ApiServer{client: mgr.GetClient(), config: mgr.GetConfig(), scheme: mgr.GetScheme()}
+// newApiServer fills in the manager-derived fields of server, registers its indexers and
+// adds it to mgr. server's caller-supplied fields (crtBytes/keyBytes, secureServingInfo,
+// listener) must already be set.
+func newApiServer(mgr ctrl.Manager, server *ApiServer) error {
+	server.client = mgr.GetClient()
+	server.config = mgr.GetConfig()
+	server.scheme = mgr.GetScheme()
+	server.stoppedCh = make(chan struct{})
 
 	if err := server.setupIndexers(mgr); err != nil {
 		return err
@@ -79,8 +213,18 @@ func (s *ApiServer) NeedLeaderElection() bool {
 }
 
 // Start starts the HTTP(S) API Server
-// if user has provided public/private cert details, it will create a TLS-auth HTTPS server
-// otherwise it will create a HTTP server with no auth
+// if user has provided a SecureServingInfo, it will create a TLS server with mutual TLS /
+// SNI / dynamic reload support; otherwise if a static public/private cert pair was
+// provided it will create a plain TLS-auth HTTPS server; otherwise it will create a HTTP
+// server with no auth. TLS servers negotiate HTTP/2 ("h2") over ALPN; the insecure server
+// can opt into cleartext HTTP/2 (h2c) by setting API_H2C=true. The underlying listener is
+// an injected one (NewApiServerWithListener), one inherited via systemd socket activation,
+// or a fresh net.Listen on API_LISTEN, in that priority order.
+//
+// Shutdown is graceful and bounded: once ctx is cancelled, /readyz immediately starts
+// reporting not-ready so load balancers stop routing new allocations, and Start gives
+// in-flight handlers up to shutdownTimeoutEnvVar (default defaultShutdownTimeout) to drain
+// before forcibly closing the server. StoppedCh can be used to observe completion.
 func (s *ApiServer) Start(ctx context.Context) error {
 	log := log.FromContext(ctx)
 	addr := os.Getenv("API_LISTEN")
@@ -94,6 +238,16 @@ func (s *ApiServer) Start(ctx context.Context) error {
 		config: s.config,
 		scheme: s.scheme,
 	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&s.ready) == 0 {
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
 
 	log.Info("serving API server", "addr", addr, "port", listeningPort)
 
@@ -102,50 +256,280 @@ func (s *ApiServer) Start(ctx context.Context) error {
 		Handler: mux,
 	}
 
-	done := make(chan struct{})
+	ln, err := s.resolveListener(addr)
+	if err != nil {
+		return fmt.Errorf("resolving listener: %w", err)
+	}
+
+	// Mark ready before the shutdown watcher goroutine is spawned below: the "go"
+	// statement happens-after this store, so the watcher's StoreInt32(0) can never be
+	// clobbered by this one, even if ctx is already cancelled by the time it runs.
+	atomic.StoreInt32(&s.ready, 1)
+
 	go func() {
 		<-ctx.Done()
 		log.Info("shutting down API server")
+		atomic.StoreInt32(&s.ready, 0)
 
-		// TODO: use a context with reasonable timeout
-		if err := srv.Shutdown(context.Background()); err != nil {
-			// Error from closing listeners, or context timeout
-			log.Error(err, "error shutting down the HTTP server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout())
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Error(err, "graceful shutdown did not complete in time, forcing close")
+			if err := srv.Close(); err != nil {
+				log.Error(err, "error force-closing the HTTP server")
+			}
 		}
-		close(done)
+		close(s.stoppedCh)
 	}()
 
-	if crtBytes != nil && keyBytes != nil {
+	switch {
+	case s.secureServingInfo != nil:
+		log.Info("starting TLS enabled API server", "mutualTLS", len(s.secureServingInfo.ClientCAPEMBlock) > 0, "sniCerts", len(s.secureServingInfo.SNICerts))
+		tlsConfig, err := buildTLSConfig(ctx, s.client, s.secureServingInfo)
+		if err != nil {
+			return err
+		}
+		if err := customListenAndServeTLSWithConfig(srv, ln, tlsConfig); err != nil && err != http.ErrServerClosed {
+			return err
+		}
+	case s.crtBytes != nil && s.keyBytes != nil:
 		log.Info("starting TLS enabled API server")
-		if err := customListenAndServeTLS(srv, crtBytes, keyBytes); err != nil && err != http.ErrServerClosed {
+		if err := customListenAndServeTLS(srv, ln, s.crtBytes, s.keyBytes); err != nil && err != http.ErrServerClosed {
 			return err
 		}
-	} else {
+	case os.Getenv(h2cEnvVar) == "true":
+		log.Info("starting insecure API server with h2c (cleartext HTTP/2) support")
+		srv.Handler = h2c.NewHandler(mux, &http2.Server{})
+		if err := customListenAndServe(srv, ln); err != nil && err != http.ErrServerClosed {
+			return err
+		}
+	default:
 		log.Info("starting insecure API server")
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := customListenAndServe(srv, ln); err != nil && err != http.ErrServerClosed {
 			return err
 		}
 	}
 
-	<-done
+	<-s.stoppedCh
 	return nil
 }
 
-// customListenAndServeTLS creates a new http server with []byte cert and []byte key
+// shutdownTimeout returns the drain deadline Start gives in-flight handlers before
+// forcing the server closed, from shutdownTimeoutEnvVar or defaultShutdownTimeout.
+func shutdownTimeout() time.Duration {
+	if v := os.Getenv(shutdownTimeoutEnvVar); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return defaultShutdownTimeout
+}
+
+// resolveListener picks the net.Listener the server should serve on, in priority order:
+// an explicitly injected listener (NewApiServerWithListener), a listener inherited via
+// systemd socket activation (LISTEN_FDS/LISTEN_PID), or a fresh net.Listen on addr.
+func (s *ApiServer) resolveListener(addr string) (net.Listener, error) {
+	if s.listener != nil {
+		return s.listener, nil
+	}
+
+	listeners, err := activation.Listeners()
+	if err != nil {
+		return nil, fmt.Errorf("getting systemd-activated listeners: %w", err)
+	}
+	if len(listeners) > 0 {
+		if listeners[0] == nil {
+			return nil, fmt.Errorf("systemd socket activation: listener 0 was not set up (LISTEN_FDS/LISTEN_PID mismatch?)")
+		}
+		return listeners[0], nil
+	}
+
+	return net.Listen("tcp", addr)
+}
+
+// certSource knows how to load the current bytes of a certificate/key pair, so a
+// certReloader can detect rotations without the server restarting.
+type certSource interface {
+	load() (certPEMBlock, keyPEMBlock []byte, err error)
+}
+
+// staticCertSource always returns the same certificate/key pair it was created with.
+type staticCertSource struct {
+	certPEMBlock, keyPEMBlock []byte
+}
+
+func (s *staticCertSource) load() ([]byte, []byte, error) {
+	return s.certPEMBlock, s.keyPEMBlock, nil
+}
+
+// fileCertSource reloads a certificate/key pair from the filesystem.
+type fileCertSource struct {
+	certFile, keyFile string
+}
+
+func (f *fileCertSource) load() ([]byte, []byte, error) {
+	certPEMBlock, err := os.ReadFile(f.certFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading cert file %s: %w", f.certFile, err)
+	}
+	keyPEMBlock, err := os.ReadFile(f.keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading key file %s: %w", f.keyFile, err)
+	}
+	return certPEMBlock, keyPEMBlock, nil
+}
+
+// secretCertSource reloads a certificate/key pair from a Kubernetes Secret's tls.crt/tls.key
+// data, by name.
+type secretCertSource struct {
+	client          client.Client
+	namespace, name string
+}
+
+func (s *secretCertSource) load() ([]byte, []byte, error) {
+	var secret corev1.Secret
+	key := client.ObjectKey{Namespace: s.namespace, Name: s.name}
+	if err := s.client.Get(context.Background(), key, &secret); err != nil {
+		return nil, nil, fmt.Errorf("getting Secret %s: %w", key, err)
+	}
+	return secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey], nil
+}
+
+// certReloader holds the currently active tls.Certificate parsed from a certSource, and
+// refreshes it on a timer so the server can pick up rotated certificates without
+// restarting or dropping in-flight connections.
+type certReloader struct {
+	source certSource
+
+	mu      sync.RWMutex
+	current *tls.Certificate
+}
+
+func newCertReloader(source certSource) (*certReloader, error) {
+	r := &certReloader{source: source}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) reload() error {
+	certPEMBlock, keyPEMBlock, err := r.source.load()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.X509KeyPair(certPEMBlock, keyPEMBlock)
+	if err != nil {
+		return fmt.Errorf("parsing certificate/key pair: %w", err)
+	}
+
+	r.mu.Lock()
+	r.current = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *certReloader) getCertificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+// watch polls the certReloader's source every interval and swaps in the latest
+// certificate, until ctx is cancelled. Reload errors are logged and otherwise ignored, so
+// a transient read failure (e.g. a Secret update still propagating) doesn't take the
+// server down.
+func (r *certReloader) watch(ctx context.Context, interval time.Duration, log logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.reload(); err != nil {
+				log.Error(err, "failed to reload API server certificate")
+			}
+		}
+	}
+}
+
+// logger is the minimal subset of logr.Logger that certReloader.watch needs, so this file
+// doesn't have to import logr directly.
+type logger interface {
+	Error(err error, msg string, keysAndValues ...interface{})
+}
+
+// buildTLSConfig assembles a *tls.Config from a SecureServingInfo: the default
+// certificate (kept fresh by a certReloader), any SNI certificates selected via
+// tls.Config.GetCertificate, and mutual TLS client authentication.
+func buildTLSConfig(ctx context.Context, c client.Client, info *SecureServingInfo) (*tls.Config, error) {
+	defaultReloader, err := newCertReloader(certSourceFor(c, info))
+	if err != nil {
+		return nil, fmt.Errorf("loading default certificate: %w", err)
+	}
+	go defaultReloader.watch(ctx, certReloadInterval, log.FromContext(ctx))
+
+	sniByName := make(map[string]*certReloader, len(info.SNICerts))
+	for _, sni := range info.SNICerts {
+		reloader, err := newCertReloader(&staticCertSource{certPEMBlock: sni.CertPEMBlock, keyPEMBlock: sni.KeyPEMBlock})
+		if err != nil {
+			return nil, fmt.Errorf("loading SNI certificate for %v: %w", sni.Names, err)
+		}
+		for _, name := range sni.Names {
+			sniByName[name] = reloader
+		}
+	}
+
+	minVersion := info.MinTLSVersion
+	if minVersion == 0 {
+		minVersion = defaultMinTLSVersion
+	}
+
+	tlsConfig := &tls.Config{
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if reloader, ok := sniByName[hello.ServerName]; ok {
+				return reloader.getCertificate(), nil
+			}
+			return defaultReloader.getCertificate(), nil
+		},
+		MinVersion:   minVersion,
+		CipherSuites: tlsCipherSuites,
+	}
+
+	if len(info.ClientCAPEMBlock) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(info.ClientCAPEMBlock) {
+			return nil, fmt.Errorf("no client CA certificates found in the provided PEM block")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+func certSourceFor(c client.Client, info *SecureServingInfo) certSource {
+	switch {
+	case info.SecretName != "":
+		return &secretCertSource{client: c, namespace: info.SecretNamespace, name: info.SecretName}
+	case info.CertFile != "":
+		return &fileCertSource{certFile: info.CertFile, keyFile: info.KeyFile}
+	default:
+		return &staticCertSource{certPEMBlock: info.CertPEMBlock, keyPEMBlock: info.KeyPEMBlock}
+	}
+}
+
+// customListenAndServeTLS serves srv over TLS on ln, using []byte cert and []byte key.
 // Golang's ListenAndServerTLS accepts filenames for cert and key whereas we have []byte
 // https://stackoverflow.com/a/30818656
-func customListenAndServeTLS(srv *http.Server, certPEMBlock, keyPEMBlock []byte) error {
-	addr := srv.Addr
-	if addr == "" {
-		addr = ":https"
-	}
+func customListenAndServeTLS(srv *http.Server, ln net.Listener, certPEMBlock, keyPEMBlock []byte) error {
 	config := &tls.Config{}
 	if srv.TLSConfig != nil {
 		config = srv.TLSConfig
 	}
-	if config.NextProtos == nil {
-		config.NextProtos = []string{"http/1.1"}
-	}
+	config.MinVersion = defaultMinTLSVersion
+	config.CipherSuites = tlsCipherSuites
 
 	var err error
 	config.Certificates = make([]tls.Certificate, 1)
@@ -154,11 +538,52 @@ func customListenAndServeTLS(srv *http.Server, certPEMBlock, keyPEMBlock []byte)
 		return err
 	}
 
-	ln, err := net.Listen("tcp", addr)
-	if err != nil {
+	return customListenAndServeTLSWithConfig(srv, ln, config)
+}
+
+// customListenAndServeTLSWithConfig serves srv over TLS on ln using a pre-built
+// tls.Config, e.g. one produced by buildTLSConfig that selects certificates dynamically
+// via GetCertificate rather than a single static tls.Config.Certificates entry. It
+// configures srv for HTTP/2, negotiating "h2" over ALPN so /api/v1/allocate can be
+// multiplexed over a single long-lived connection.
+func customListenAndServeTLSWithConfig(srv *http.Server, ln net.Listener, tlsConfig *tls.Config) error {
+	srv.TLSConfig = tlsConfig
+	if err := http2.ConfigureServer(srv, &http2.Server{}); err != nil {
 		return err
 	}
 
-	tlsListener := tls.NewListener(tcpKeepAliveListener{ln.(*net.TCPListener)}, config)
+	tlsListener := tls.NewListener(withTCPKeepAlive(ln), srv.TLSConfig)
 	return srv.Serve(tlsListener)
 }
+
+// customListenAndServe serves srv in cleartext (no TLS) on ln, applying the same TCP
+// keep-alive tuning as the TLS path.
+func customListenAndServe(srv *http.Server, ln net.Listener) error {
+	return srv.Serve(withTCPKeepAlive(ln))
+}
+
+// withTCPKeepAlive wraps ln with tcpKeepAliveListener when it's a *net.TCPListener (the
+// common case); listeners of other kinds (Unix sockets, bufconn, ones inherited via
+// systemd socket activation with a non-TCP type, ...) are returned unchanged.
+func withTCPKeepAlive(ln net.Listener) net.Listener {
+	if tcpLn, ok := ln.(*net.TCPListener); ok {
+		return tcpKeepAliveListener{tcpLn}
+	}
+	return ln
+}
+
+// tcpKeepAliveListener sets TCP keep-alive timeouts on accepted connections, so dead TCP
+// connections (e.g. a laptop closing its lid mid-allocation) eventually go away.
+type tcpKeepAliveListener struct {
+	*net.TCPListener
+}
+
+func (ln tcpKeepAliveListener) Accept() (net.Conn, error) {
+	tc, err := ln.AcceptTCP()
+	if err != nil {
+		return nil, err
+	}
+	tc.SetKeepAlive(true)
+	tc.SetKeepAlivePeriod(tcpKeepAlivePeriod)
+	return tc, nil
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+// generateSelfSignedCert returns a PEM-encoded self-signed certificate/key pair for cn,
+// valid for loopback connections.
+func generateSelfSignedCert(t *testing.T, cn string) ([]byte, []byte) {
+	t.Helper()
+
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+	return certPEM, keyPEM
+}
+
+// dialAndGetLeafCN dials addr over TLS and returns the CommonName of the certificate the
+// server presents, retrying briefly while the server finishes starting up.
+func dialAndGetLeafCN(t *testing.T, addr string) string {
+	t.Helper()
+
+	var conn *tls.Conn
+	var err error
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err = tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("dialing %s: %v", addr, err)
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		t.Fatalf("no peer certificates presented by %s", addr)
+	}
+	return state.PeerCertificates[0].Subject.CommonName
+}
+
+// TestApiServerTLSConfigIsPerInstance guards against crtBytes/keyBytes/secureServingInfo
+// regressing into package-level globals: two ApiServer instances constructed with
+// different certificates (one via crtBytes/keyBytes, one via secureServingInfo), each
+// given its own injected listener (the field NewApiServerWithListener sets), must each
+// serve their own certificate rather than whichever config was set last.
+func TestApiServerTLSConfigIsPerInstance(t *testing.T) {
+	cert1, key1 := generateSelfSignedCert(t, "server-one")
+	cert2, key2 := generateSelfSignedCert(t, "server-two")
+
+	ln1, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	ln2, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+
+	s1 := &ApiServer{crtBytes: cert1, keyBytes: key1, listener: ln1, stoppedCh: make(chan struct{})}
+	s2 := &ApiServer{secureServingInfo: &SecureServingInfo{CertPEMBlock: cert2, KeyPEMBlock: key2}, listener: ln2, stoppedCh: make(chan struct{})}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel1()
+	defer cancel2()
+
+	go s1.Start(ctx1)
+	go s2.Start(ctx2)
+
+	if cn := dialAndGetLeafCN(t, ln1.Addr().String()); cn != "server-one" {
+		t.Errorf("server 1 presented CN %q, want %q", cn, "server-one")
+	}
+	if cn := dialAndGetLeafCN(t, ln2.Addr().String()); cn != "server-two" {
+		t.Errorf("server 2 presented CN %q, want %q", cn, "server-two")
+	}
+
+	cancel1()
+	cancel2()
+	<-s1.StoppedCh()
+	<-s2.StoppedCh()
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestReadyFlagNeverFlipsBackAfterShutdownBegins guards against the ready flag being set
+// to 1 after the shutdown watcher goroutine has already set it to 0: Start is invoked with
+// an already-cancelled context, reproducing the worst-case scheduling order where the
+// watcher goroutine runs before Start finishes its own setup. Once StoppedCh closes, ready
+// must never read back as 1.
+func TestReadyFlagNeverFlipsBackAfterShutdownBegins(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+
+	s := &ApiServer{listener: ln, stoppedCh: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- s.Start(ctx) }()
+
+	select {
+	case <-s.stoppedCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop after context cancellation")
+	}
+
+	if ready := atomic.LoadInt32(&s.ready); ready != 0 {
+		t.Errorf("ready flag is %d after shutdown completed, want 0", ready)
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("Start returned error: %v", err)
+	}
+}